@@ -0,0 +1,82 @@
+// Package cmd wires CLI flags to a pkg/crawl.Crawl and starts it.
+package cmd
+
+import (
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/CorentinB/Zeno/pkg/crawl"
+	"github.com/CorentinB/Zeno/pkg/queue"
+	"github.com/paulbellamy/ratecounter"
+	"github.com/remeh/sizedwaitgroup"
+)
+
+// stringSliceFlag collects repeated occurrences of the same flag, e.g.
+// "--exclude a --exclude b", into a slice.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// NewCrawlFromFlags parses args against the crawl flag set and returns a
+// ready-to-Start *crawl.Crawl.
+func NewCrawlFromFlags(args []string) (*crawl.Crawl, error) {
+	flags := flag.NewFlagSet("crawl", flag.ExitOnError)
+
+	jobPath := flags.String("job", "", "path of the job's working directory")
+	headless := flags.Bool("headless", false, "use a headless browser to render JS-heavy pages")
+	maxHops := flags.Uint("max-hops", 0, "maximum number of hops to follow from a seed")
+	warcPrefix := flags.String("warc-prefix", "ZENO", "prefix used for WARC file names")
+	warcOperator := flags.String("warc-operator", "", "operator recorded in the WARC's warcinfo record")
+	outputMaxSize := flags.Int("output-max-size", 100, "maximum post-compression size, in MB, of a single WARC segment")
+	resume := flags.String("resume", "", "reopen an existing job directory and replay its outstanding frontier")
+
+	var exclude stringSliceFlag
+	flags.Var(&exclude, "exclude", "regexp an outlink must not match to be enqueued (repeatable)")
+	excludeFromFile := flags.String("exclude-from-file", "", "path to a file of exclusion regexps, one per line, # comments allowed")
+	schemes := flags.String("schemes", "http,https", "comma-separated list of schemes allowed to be enqueued")
+	excludeRelated := flags.Bool("exclude-related", false, "only keep a page's embedded assets when their host matches the seed's")
+
+	bind := flags.String("bind", "", "local address to bind outbound connections to")
+	var resolve stringSliceFlag
+	flags.Var(&resolve, "resolve", "override DNS for a host, as host=ip (repeatable)")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	scope, err := crawl.NewScope(exclude, *excludeFromFile, strings.Split(*schemes, ","), *excludeRelated)
+	if err != nil {
+		return nil, err
+	}
+
+	resolveMap, err := crawl.ParseResolveFlag(resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &crawl.Crawl{
+		JobPath:       *jobPath,
+		Headless:      *headless,
+		MaxHops:       uint8(*maxHops),
+		WARCPrefix:    *warcPrefix,
+		WARCOperator:  *warcOperator,
+		WARCMaxSizeMB: *outputMaxSize,
+		Resume:        *resume,
+		Scope:         scope,
+		Bind:          *bind,
+		Resolve:       resolveMap,
+		Queue:         queue.NewQueue(),
+		URLsPerSecond: ratecounter.NewRateCounter(time.Second),
+		WaitGroup:     sizedwaitgroup.New(1),
+	}
+
+	return c, nil
+}
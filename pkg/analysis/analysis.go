@@ -0,0 +1,99 @@
+// Package analysis extracts outlinks from a captured response body,
+// dispatching to a format-specific extractor based on the response's
+// Content-Type so that assets referenced from stylesheets, sitemaps, and
+// HTML are all discovered, not just the links found in plain HTML.
+package analysis
+
+import (
+	"io"
+	"net/url"
+	"strings"
+)
+
+// LinkType distinguishes a navigational link from an embedded asset, so
+// callers can apply different scoping rules to each (see the crawler's
+// --exclude-related flag).
+type LinkType string
+
+const (
+	// LinkTypeLink is a plain navigational link, e.g. an <a href>, a
+	// sitemap <loc>, or a CSS @import.
+	LinkTypeLink LinkType = "link"
+	// LinkTypeAsset is a resource embedded into the page itself, e.g. an
+	// <img src>, a <script src>, or a CSS url(...).
+	LinkTypeAsset LinkType = "asset"
+)
+
+// Outlink is a single URL found in a document, tagged with how it was
+// referenced.
+type Outlink struct {
+	URL  url.URL
+	Type LinkType
+}
+
+// GetLinks reads body and returns every outlink it can find, resolved
+// against base. The extractor used is picked from contentType; anything
+// we don't recognize falls back to the HTML extractor, since most
+// servers still send text/plain or no header at all for HTML pages.
+func GetLinks(contentType string, base *url.URL, body io.Reader) (outlinks []Outlink, err error) {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+
+	switch {
+	case strings.Contains(mediaType, "css"):
+		outlinks, err = extractFromCSS(base, body)
+	case isSitemapContentType(mediaType):
+		outlinks, err = extractFromSitemap(base, body)
+	default:
+		outlinks, err = extractFromHTML(base, body)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return dedupe(outlinks), nil
+}
+
+// isSitemapContentType reports whether mediaType is a bare XML type, as
+// used for sitemap.xml, rather than an XML-based document format like
+// application/xhtml+xml that should still go through the HTML extractor.
+func isSitemapContentType(mediaType string) bool {
+	switch mediaType {
+	case "application/xml", "text/xml":
+		return true
+	default:
+		return false
+	}
+}
+
+func dedupe(in []Outlink) (out []Outlink) {
+	seen := make(map[string]bool, len(in))
+
+	for _, link := range in {
+		key := link.URL.String()
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		out = append(out, link)
+	}
+
+	return out
+}
+
+// resolve turns a raw, possibly-relative reference found in a document
+// into an absolute URL relative to base. It returns false if ref can't
+// be parsed or resolved.
+func resolve(base *url.URL, ref string) (url.URL, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "data:") || strings.HasPrefix(ref, "javascript:") {
+		return url.URL{}, false
+	}
+
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return url.URL{}, false
+	}
+
+	return *base.ResolveReference(parsed), true
+}
@@ -0,0 +1,133 @@
+package analysis
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func mustParseBase(t *testing.T) *url.URL {
+	t.Helper()
+
+	base, err := url.Parse("https://example.com/page")
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	return base
+}
+
+func TestGetLinksDispatchesByContentType(t *testing.T) {
+	base := mustParseBase(t)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        []string
+	}{
+		{
+			name:        "html",
+			contentType: "text/html; charset=utf-8",
+			body:        `<a href="/a">a</a>`,
+			want:        []string{"https://example.com/a"},
+		},
+		{
+			name:        "css",
+			contentType: "text/css",
+			body:        `body { background: url("/bg.png"); }`,
+			want:        []string{"https://example.com/bg.png"},
+		},
+		{
+			name:        "sitemap xml",
+			contentType: "application/xml",
+			body:        `<urlset><url><loc>https://example.com/one</loc></url></urlset>`,
+			want:        []string{"https://example.com/one"},
+		},
+		{
+			name:        "xhtml is not a sitemap",
+			contentType: "application/xhtml+xml",
+			body:        `<a href="/a">a</a>`,
+			want:        []string{"https://example.com/a"},
+		},
+		{
+			name:        "unrecognized content type falls back to html",
+			contentType: "",
+			body:        `<a href="/a">a</a>`,
+			want:        []string{"https://example.com/a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outlinks, err := GetLinks(tt.contentType, base, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("GetLinks() error = %v", err)
+			}
+
+			if len(outlinks) != len(tt.want) {
+				t.Fatalf("GetLinks() = %v, want %v", outlinks, tt.want)
+			}
+			for i, link := range outlinks {
+				if link.URL.String() != tt.want[i] {
+					t.Errorf("outlinks[%d] = %q, want %q", i, link.URL.String(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIsSitemapContentType(t *testing.T) {
+	tests := []struct {
+		mediaType string
+		want      bool
+	}{
+		{"application/xml", true},
+		{"text/xml", true},
+		{"application/xhtml+xml", false},
+		{"text/html", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isSitemapContentType(tt.mediaType); got != tt.want {
+			t.Errorf("isSitemapContentType(%q) = %v, want %v", tt.mediaType, got, tt.want)
+		}
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	base := mustParseBase(t)
+	body := `<a href="/a">a</a><a href="/a">again</a><a href="/b">b</a>`
+
+	outlinks, err := GetLinks("text/html", base, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("GetLinks() error = %v", err)
+	}
+
+	if len(outlinks) != 2 {
+		t.Fatalf("GetLinks() returned %d outlinks, want 2 after dedupe: %v", len(outlinks), outlinks)
+	}
+}
+
+func TestResolveSkipsDataAndJavascriptURLs(t *testing.T) {
+	base := mustParseBase(t)
+
+	if _, ok := resolve(base, "data:image/png;base64,aaaa"); ok {
+		t.Error("resolve() accepted a data: URL, want it skipped")
+	}
+	if _, ok := resolve(base, "javascript:void(0)"); ok {
+		t.Error("resolve() accepted a javascript: URL, want it skipped")
+	}
+	if _, ok := resolve(base, ""); ok {
+		t.Error("resolve() accepted an empty ref, want it skipped")
+	}
+
+	resolved, ok := resolve(base, "/relative")
+	if !ok {
+		t.Fatal("resolve() rejected a plain relative ref, want it accepted")
+	}
+	if resolved.String() != "https://example.com/relative" {
+		t.Errorf("resolve() = %q, want %q", resolved.String(), "https://example.com/relative")
+	}
+}
@@ -0,0 +1,37 @@
+package analysis
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+)
+
+var (
+	cssURLRegexp    = regexp.MustCompile(`url\(["']?([^'")]+)["']?\)`)
+	cssImportRegexp = regexp.MustCompile(`@import\s+["']([^'"]+)["']`)
+)
+
+// extractFromCSS scans a stylesheet for url(...) references (background
+// images, fonts, etc.) and @import rules, resolving both against base.
+func extractFromCSS(base *url.URL, body io.Reader) (outlinks []Outlink, err error) {
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, match := range cssURLRegexp.FindAllSubmatch(content, -1) {
+		if resolved, ok := resolve(base, string(match[1])); ok {
+			outlinks = append(outlinks, Outlink{URL: resolved, Type: LinkTypeAsset})
+		}
+	}
+
+	for _, match := range cssImportRegexp.FindAllSubmatch(bytes.TrimSpace(content), -1) {
+		if resolved, ok := resolve(base, string(match[1])); ok {
+			outlinks = append(outlinks, Outlink{URL: resolved, Type: LinkTypeLink})
+		}
+	}
+
+	return outlinks, nil
+}
@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestExtractFromCSS(t *testing.T) {
+	base, err := url.Parse("https://example.com/css/style.css")
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	body := `
+		@import "reset.css";
+		@import 'theme.css';
+		.a { background: url(bg.png); }
+		.b { background: url("bg2.png"); }
+		.c { background: url('bg3.png'); }
+		.d { background: url(data:image/png;base64,aaaa); }
+	`
+
+	outlinks, err := extractFromCSS(base, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("extractFromCSS() error = %v", err)
+	}
+
+	want := []string{
+		"https://example.com/css/bg.png",
+		"https://example.com/css/bg2.png",
+		"https://example.com/css/bg3.png",
+		"https://example.com/css/reset.css",
+		"https://example.com/css/theme.css",
+	}
+
+	got := make(map[string]LinkType, len(outlinks))
+	for _, link := range outlinks {
+		got[link.URL.String()] = link.Type
+	}
+
+	for _, url := range want {
+		if _, ok := got[url]; !ok {
+			t.Errorf("extractFromCSS() missing %q, got %v", url, outlinks)
+		}
+	}
+	if len(outlinks) != len(want) {
+		t.Errorf("extractFromCSS() returned %d outlinks, want %d: %v", len(outlinks), len(want), outlinks)
+	}
+
+	if got["https://example.com/css/bg.png"] != LinkTypeAsset {
+		t.Errorf("url(...) outlink tagged %q, want %q", got["https://example.com/css/bg.png"], LinkTypeAsset)
+	}
+	if got["https://example.com/css/reset.css"] != LinkTypeLink {
+		t.Errorf("@import outlink tagged %q, want %q", got["https://example.com/css/reset.css"], LinkTypeLink)
+	}
+}
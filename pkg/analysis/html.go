@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// htmlTarget is a (tag, attribute) pair worth walking the DOM for, e.g.
+// <img src="..."> or <link href="...">, tagged with whether a match is a
+// navigational link or an embedded asset.
+type htmlTarget struct {
+	tag      string
+	attr     string
+	linkType LinkType
+}
+
+var htmlTargets = []htmlTarget{
+	{"a", "href", LinkTypeLink},
+	{"link", "href", LinkTypeAsset},
+	{"img", "src", LinkTypeAsset},
+	{"script", "src", LinkTypeAsset},
+	{"iframe", "src", LinkTypeAsset},
+	{"source", "srcset", LinkTypeAsset},
+	{"video", "poster", LinkTypeAsset},
+	{"embed", "src", LinkTypeAsset},
+}
+
+// extractFromHTML walks the document looking for every (tag, attr) pair
+// in htmlTargets and resolves whatever it finds against base.
+func extractFromHTML(base *url.URL, body io.Reader) (outlinks []Outlink, err error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, target := range htmlTargets {
+		doc.Find(target.tag + "[" + target.attr + "]").Each(func(_ int, sel *goquery.Selection) {
+			raw, exists := sel.Attr(target.attr)
+			if !exists {
+				return
+			}
+
+			if target.attr == "srcset" {
+				for _, candidate := range parseSrcset(raw) {
+					if resolved, ok := resolve(base, candidate); ok {
+						outlinks = append(outlinks, Outlink{URL: resolved, Type: target.linkType})
+					}
+				}
+				return
+			}
+
+			if resolved, ok := resolve(base, raw); ok {
+				outlinks = append(outlinks, Outlink{URL: resolved, Type: target.linkType})
+			}
+		})
+	}
+
+	return outlinks, nil
+}
+
+// parseSrcset splits a srcset attribute ("a.jpg 1x, b.jpg 2x") into its
+// bare URL candidates, dropping the descriptors.
+func parseSrcset(raw string) (urls []string) {
+	for _, candidate := range strings.Split(raw, ",") {
+		fields := strings.Fields(strings.TrimSpace(candidate))
+		if len(fields) > 0 {
+			urls = append(urls, fields[0])
+		}
+	}
+
+	return urls
+}
@@ -0,0 +1,68 @@
+package analysis
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractFromHTML(t *testing.T) {
+	base, err := url.Parse("https://example.com/page")
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	body := `
+		<a href="/a">a</a>
+		<link href="/style.css">
+		<img src="/logo.png">
+		<script src="/app.js"></script>
+		<a href="javascript:void(0)">noop</a>
+	`
+
+	outlinks, err := extractFromHTML(base, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("extractFromHTML() error = %v", err)
+	}
+
+	want := map[string]LinkType{
+		"https://example.com/a":         LinkTypeLink,
+		"https://example.com/style.css": LinkTypeAsset,
+		"https://example.com/logo.png":  LinkTypeAsset,
+		"https://example.com/app.js":    LinkTypeAsset,
+	}
+
+	if len(outlinks) != len(want) {
+		t.Fatalf("extractFromHTML() returned %d outlinks, want %d: %v", len(outlinks), len(want), outlinks)
+	}
+	for _, link := range outlinks {
+		wantType, ok := want[link.URL.String()]
+		if !ok {
+			t.Errorf("extractFromHTML() returned unexpected outlink %q", link.URL.String())
+			continue
+		}
+		if link.Type != wantType {
+			t.Errorf("outlink %q tagged %q, want %q", link.URL.String(), link.Type, wantType)
+		}
+	}
+}
+
+func TestParseSrcset(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{"a.jpg 1x, b.jpg 2x", []string{"a.jpg", "b.jpg"}},
+		{"a.jpg", []string{"a.jpg"}},
+		{"a.jpg 100w,   b.jpg 200w", []string{"a.jpg", "b.jpg"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		got := parseSrcset(tt.raw)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSrcset(%q) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
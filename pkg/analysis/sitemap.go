@@ -0,0 +1,37 @@
+package analysis
+
+import (
+	"encoding/xml"
+	"io"
+	"net/url"
+)
+
+// sitemapURLSet matches both a <urlset> of pages and a <sitemapindex> of
+// sub-sitemaps: both use a <loc> child on their respective repeated
+// element, so one struct covers either shape.
+type sitemapURLSet struct {
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// extractFromSitemap parses a sitemap.xml (or sitemap index) and yields
+// every <loc> it contains, resolved against base.
+func extractFromSitemap(base *url.URL, body io.Reader) (outlinks []Outlink, err error) {
+	var set sitemapURLSet
+
+	if err := xml.NewDecoder(body).Decode(&set); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range append(set.URLs, set.Sitemaps...) {
+		if resolved, ok := resolve(base, entry.Loc); ok {
+			outlinks = append(outlinks, Outlink{URL: resolved, Type: LinkTypeLink})
+		}
+	}
+
+	return outlinks, nil
+}
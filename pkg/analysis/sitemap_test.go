@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestExtractFromSitemapURLSet(t *testing.T) {
+	base, err := url.Parse("https://example.com/sitemap.xml")
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+		<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+			<url><loc>https://example.com/one</loc></url>
+			<url><loc>https://example.com/two</loc></url>
+		</urlset>`
+
+	outlinks, err := extractFromSitemap(base, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("extractFromSitemap() error = %v", err)
+	}
+
+	want := []string{"https://example.com/one", "https://example.com/two"}
+	if len(outlinks) != len(want) {
+		t.Fatalf("extractFromSitemap() = %v, want %v", outlinks, want)
+	}
+	for i, link := range outlinks {
+		if link.URL.String() != want[i] {
+			t.Errorf("outlinks[%d] = %q, want %q", i, link.URL.String(), want[i])
+		}
+		if link.Type != LinkTypeLink {
+			t.Errorf("outlinks[%d] tagged %q, want %q", i, link.Type, LinkTypeLink)
+		}
+	}
+}
+
+func TestExtractFromSitemapIndex(t *testing.T) {
+	base, err := url.Parse("https://example.com/sitemap-index.xml")
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+		<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+			<sitemap><loc>https://example.com/sitemap-1.xml</loc></sitemap>
+		</sitemapindex>`
+
+	outlinks, err := extractFromSitemap(base, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("extractFromSitemap() error = %v", err)
+	}
+
+	if len(outlinks) != 1 || outlinks[0].URL.String() != "https://example.com/sitemap-1.xml" {
+		t.Fatalf("extractFromSitemap() = %v, want [https://example.com/sitemap-1.xml]", outlinks)
+	}
+}
@@ -3,11 +3,10 @@ package crawl
 import (
 	"context"
 	"github.com/CorentinB/Zeno/pkg/utils"
-	"io/ioutil"
-	"net/http"
-	"net/url"
+	"os"
 	"strings"
 
+	"github.com/CorentinB/Zeno/pkg/analysis"
 	"github.com/CorentinB/Zeno/pkg/queue"
 	"github.com/chromedp/cdproto/dom"
 	"github.com/chromedp/cdproto/network"
@@ -15,7 +14,16 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func (c *Crawl) captureWithBrowser(ctx context.Context, item *queue.Item) (outlinks []url.URL, err error) {
+func (c *Crawl) captureWithBrowser(ctx context.Context, item *queue.Item) (outlinks []analysis.Outlink, err error) {
+	// Chrome has no direct equivalent of --bind, so only --resolve is
+	// threaded through here, via a --host-resolver-rules flag on the
+	// allocator. The shared http.Client above handles both for captureWithGET.
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, c.browserAllocatorOptions()...)
+	defer allocCancel()
+
+	ctx, cancel := chromedp.NewContext(allocCtx)
+	defer cancel()
+
 	// Log requests
 	chromedp.ListenTarget(ctx, func(ev interface{}) {
 		switch ev := ev.(type) {
@@ -43,20 +51,22 @@ func (c *Crawl) captureWithBrowser(ctx context.Context, item *queue.Item) (outli
 		network.Enable(),
 		chromedp.Navigate(item.URL.String()),
 		chromedp.ActionFunc(func(ctx context.Context) error {
-			if c.MaxHops > 0 {
-				// Extract outer HTML
-				node, err := dom.GetDocument().Do(ctx)
-				if err != nil {
-					return err
-				}
-				str, err := dom.GetOuterHTML().WithNodeID(node.NodeID).Do(ctx)
-				if err != nil {
-					return err
-				}
-
-				// Extract outlinks
-				outlinks = extractOutlinks(str)
+			// Extract outer HTML
+			node, err := dom.GetDocument().Do(ctx)
+			if err != nil {
+				return err
+			}
+			str, err := dom.GetOuterHTML().WithNodeID(node.NodeID).Do(ctx)
+			if err != nil {
+				return err
+			}
 
+			// Extract outlinks. Rendered pages are always HTML by the
+			// time chromedp hands them back to us. MaxHops is applied
+			// once, centrally, by Capture below - not here - so this
+			// always extracts, the same way captureWithGET always does.
+			outlinks, err = analysis.GetLinks("text/html", &item.URL, strings.NewReader(str))
+			if err != nil {
 				return err
 			}
 
@@ -70,12 +80,14 @@ func (c *Crawl) captureWithBrowser(ctx context.Context, item *queue.Item) (outli
 	return outlinks, nil
 }
 
-func (c *Crawl) captureWithGET(ctx context.Context, item *queue.Item) (outlinks []url.URL, err error) {
-	// Execute GET request
-	resp, err := http.Get(item.URL.String())
+func (c *Crawl) captureWithGET(ctx context.Context, item *queue.Item) (outlinks []analysis.Outlink, err error) {
+	// Execute GET request through the shared client, so --bind and
+	// --resolve apply here the same way they do to the browser path.
+	resp, err := c.Client.Get(item.URL.String())
 	if err != nil {
 		return outlinks, err
 	}
+	defer resp.Body.Close()
 
 	log.WithFields(log.Fields{
 		"rate": c.URLsPerSecond.Rate(),
@@ -84,20 +96,58 @@ func (c *Crawl) captureWithGET(ctx context.Context, item *queue.Item) (outlinks
 		"hop":         item.Hop,
 	}).Info(item.URL.String())
 
-	// Read body
-	body, err := ioutil.ReadAll(resp.Body)
+	// Stream the body to disk as it comes in, instead of buffering it in
+	// RAM, so a single huge or chunked response can't OOM the crawler.
+	// Both the link extractor and the WARC writer below read it back
+	// from disk, seeking to the start between passes.
+	bodyFile, err := c.dumpBodyToFile(resp.Body)
 	if err != nil {
 		return outlinks, err
 	}
+	defer os.Remove(bodyFile.Name())
+	defer bodyFile.Close()
 
-	// Extract outlinks
-	outlinks = extractOutlinks(string(body))
+	// Extract outlinks, dispatching on the response's Content-Type so
+	// that stylesheets, sitemaps, and HTML are each parsed the way they
+	// need to be instead of being treated as HTML by default.
+	outlinks, err = analysis.GetLinks(resp.Header.Get("Content-Type"), resp.Request.URL, bodyFile)
+	if err != nil {
+		return outlinks, err
+	}
+
+	if err = c.writeWARC(resp, bodyFile); err != nil {
+		return outlinks, err
+	}
 
 	return outlinks, nil
 }
 
-// Capture capture a page and queue the outlinks
-func (c *Crawl) Capture(ctx context.Context, item *queue.Item) (outlinks []url.URL, err error) {
+// Capture captures a page and returns the outlinks it found that pass
+// scope, each tagged with whether it was a navigational link or an
+// embedded asset so callers can treat the two differently downstream.
+func (c *Crawl) Capture(ctx context.Context, item *queue.Item) (outItems []*queue.Item, err error) {
+	// On --resume, the state DB already knows about this item. If it was
+	// already fetched and flushed to WARC in a previous run, skip it so
+	// we don't re-fetch it or duplicate its WARC record.
+	if c.StateDB != nil {
+		var done bool
+
+		done, err = c.StateDB.IsDone(item.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if done {
+			return nil, nil
+		}
+
+		if err = c.StateDB.Transition(item.Hash, queue.StatusInProgress); err != nil {
+			return nil, err
+		}
+	}
+
+	var outlinks []analysis.Outlink
+
 	// Check with HTTP HEAD request if the URL need a full headless browser or a simple GET request
 	if needBrowser(item) && c.Headless == true {
 		outlinks, err = c.captureWithBrowser(ctx, item)
@@ -110,5 +160,65 @@ func (c *Crawl) Capture(ctx context.Context, item *queue.Item) (outlinks []url.U
 		return nil, err
 	}
 
-	return outlinks, nil
+	if c.StateDB != nil {
+		if err = c.StateDB.Transition(item.Hash, queue.StatusDone); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Scope != nil {
+		outlinks = c.Scope.Filter(outlinks, &item.URL)
+	}
+
+	// Capture is the only place that turns outlinks into Items, so it's
+	// also the only place that applies MaxHops - Start just enqueues
+	// whatever we hand back, with no hop math of its own, so a hop is
+	// never counted twice.
+	if c.MaxHops > 0 && item.Hop >= c.MaxHops {
+		return nil, nil
+	}
+
+	for _, outlink := range outlinks {
+		itemType := queue.ItemTypeLink
+		if outlink.Type == analysis.LinkTypeAsset {
+			itemType = queue.ItemTypeAsset
+		}
+
+		outItems = append(outItems, &queue.Item{
+			URL:        outlink.URL,
+			Hash:       utils.GetSHA1(outlink.URL.String()),
+			Hop:        item.Hop + 1,
+			Type:       itemType,
+			ParentItem: item,
+		})
+	}
+
+	return outItems, nil
+}
+
+// browserAllocatorOptions builds the chromedp allocator options for this
+// crawl, applying --resolve as a Chrome --host-resolver-rules flag.
+func (c *Crawl) browserAllocatorOptions() []chromedp.ExecAllocatorOption {
+	options := chromedp.DefaultExecAllocatorOptions[:]
+
+	if rules := hostResolverRules(c.Resolve); rules != "" {
+		options = append(options, chromedp.Flag("host-resolver-rules", rules))
+	}
+
+	return options
+}
+
+// hostResolverRules turns a --resolve map into the comma-separated
+// MAP host:* ip rules Chrome's --host-resolver-rules flag expects.
+func hostResolverRules(resolve map[string]string) string {
+	if len(resolve) == 0 {
+		return ""
+	}
+
+	rules := make([]string, 0, len(resolve))
+	for host, ip := range resolve {
+		rules = append(rules, "MAP "+host+":* "+ip)
+	}
+
+	return strings.Join(rules, ",")
 }
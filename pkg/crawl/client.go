@@ -0,0 +1,59 @@
+package crawl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ParseResolveFlag turns a repeated "--resolve host=ip" flag into the map
+// Crawl.Resolve expects, so a single capture dialer can consult it for
+// every fetch.
+func ParseResolveFlag(values []string) (map[string]string, error) {
+	resolve := make(map[string]string, len(values))
+
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid --resolve value %q, expected host=ip", value)
+		}
+
+		resolve[parts[0]] = parts[1]
+	}
+
+	return resolve, nil
+}
+
+// initHTTPClient builds the *http.Client every captureWithGET call
+// shares, so connection pooling, --bind, and --resolve all apply
+// uniformly instead of each fetch opening its own bare connection.
+func (c *Crawl) initHTTPClient() {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	if c.Bind != "" {
+		dialer.LocalAddr = &net.TCPAddr{IP: net.ParseIP(c.Bind)}
+	}
+
+	c.Client = &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				host, port, err := net.SplitHostPort(addr)
+				if err != nil {
+					return dialer.DialContext(ctx, network, addr)
+				}
+
+				if override, ok := c.Resolve[host]; ok {
+					addr = net.JoinHostPort(override, port)
+				}
+
+				return dialer.DialContext(ctx, network, addr)
+			},
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package crawl
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/CorentinB/Zeno/pkg/queue"
+	"github.com/CorentinB/warc"
+	"github.com/paulbellamy/ratecounter"
+	"github.com/remeh/sizedwaitgroup"
+	"github.com/sirupsen/logrus"
+)
+
+// Crawl holds the configuration and runtime state of a single crawl job,
+// from the in-memory frontier down to the WARC writer.
+type Crawl struct {
+	Headless bool
+	MaxHops  uint8
+
+	JobPath      string
+	WARCPrefix   string
+	WARCOperator string
+	// WARCMaxSizeMB caps the post-compression size of a single WARC
+	// segment, exposed as --output-max-size.
+	WARCMaxSizeMB int
+
+	WARCWriter       chan *warc.RecordBatch
+	WARCWriterFinish chan bool
+
+	// Client is the shared HTTP client every captureWithGET call uses, so
+	// connection pooling and the --bind / --resolve overrides below apply
+	// uniformly across the whole crawl.
+	Client *http.Client
+	// Bind pins outbound connections to a specific local address
+	// (--bind). Empty lets the OS pick.
+	Bind string
+	// Resolve overrides DNS for specific hosts (repeatable --resolve
+	// host=ip), consulted by Client's dialer.
+	Resolve map[string]string
+
+	// Scope decides which outlinks discovered during a capture are
+	// allowed to be enqueued.
+	Scope *Scope
+
+	// Queue is the in-memory frontier workers pull from.
+	Queue *queue.Queue
+	// StateDB persists the frontier to disk so a crawl can be resumed.
+	StateDB *queue.StateDB
+	// Resume, when set to a previous job's directory, reopens that job's
+	// state DB and replays its outstanding items before crawling starts.
+	Resume string
+
+	URLsPerSecond *ratecounter.RateCounter
+	WaitGroup     sizedwaitgroup.SizedWaitGroup
+}
+
+// Enqueue pushes item onto the in-memory frontier and, if persistent
+// state is enabled, records it as pending so an interrupted crawl can
+// pick it back up with --resume. If the state DB already knows about
+// item's hash - because it was discovered twice in this run, or carried
+// over from a previous one - Enqueue does nothing, so an ordinary
+// repeated link (nav bar, logo, a page linking back to one already
+// crawled) doesn't get queued and fetched a second time.
+func (c *Crawl) Enqueue(item *queue.Item) error {
+	if c.StateDB != nil {
+		known, err := c.StateDB.Known(item.Hash)
+		if err != nil {
+			return err
+		}
+
+		if known {
+			return nil
+		}
+
+		if err := c.StateDB.Put(item); err != nil {
+			return err
+		}
+	}
+
+	c.Queue.Enqueue(item)
+
+	return nil
+}
+
+// Start wires up the crawl's dependencies, replays any outstanding
+// frontier state on --resume, then drains the queue until it's empty.
+func (c *Crawl) Start() error {
+	c.initHTTPClient()
+	c.initWARCWriter()
+	c.initStateDB()
+
+	if c.Resume != "" {
+		if err := c.replayOutstanding(); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+
+	for {
+		item, ok := c.Queue.Dequeue()
+		if !ok {
+			break
+		}
+
+		// Capture is the only place that turns outlinks into Items, and
+		// the only place that applies MaxHops, so Start just enqueues
+		// whatever it hands back with no hop math of its own - a hop is
+		// never counted twice.
+		outItems, err := c.Capture(ctx, item)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"error": err,
+				"url":   item.URL.String(),
+			}).Warn("Error while capturing item")
+			continue
+		}
+
+		for _, outItem := range outItems {
+			if err := c.Enqueue(outItem); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"error": err,
+					"url":   outItem.URL.String(),
+				}).Warn("Error while enqueuing outlink")
+			}
+		}
+	}
+
+	c.WARCWriterFinish <- true
+
+	if c.StateDB != nil {
+		return c.StateDB.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package crawl
+
+import (
+	"net/url"
+
+	"github.com/CorentinB/Zeno/pkg/queue"
+	"github.com/sirupsen/logrus"
+)
+
+// initStateDB opens the on-disk frontier database for this job. It is
+// always opened, --resume or not, so that a future resume has something
+// to replay from.
+func (c *Crawl) initStateDB() {
+	var err error
+
+	c.StateDB, err = queue.OpenStateDB(c.JobPath)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error": err,
+		}).Fatal("Error when initializing state DB")
+	}
+}
+
+// replayOutstanding reopens the state DB for an existing job directory
+// and pushes every item that wasn't marked done back onto the in-memory
+// queue, so a crawl started with --resume picks up where it left off.
+func (c *Crawl) replayOutstanding() error {
+	records, err := c.StateDB.Outstanding()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		parsedURL, err := url.Parse(record.URL)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"error": err,
+				"url":   record.URL,
+			}).Warn("Skipping unparsable URL from state DB on resume")
+			continue
+		}
+
+		c.Queue.Enqueue(&queue.Item{
+			URL:  *parsedURL,
+			Hash: record.Hash,
+			Hop:  record.Hop,
+		})
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"count": len(records),
+	}).Info("Replayed outstanding items from state DB")
+
+	return nil
+}
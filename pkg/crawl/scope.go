@@ -0,0 +1,120 @@
+package crawl
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/CorentinB/Zeno/pkg/analysis"
+)
+
+// Scope decides whether a URL discovered during a capture is allowed to
+// be enqueued. It is consulted by Capture right after the link extractor
+// returns, once per outlink.
+type Scope struct {
+	// exclusions is checked first: any URL matching one of these regexps
+	// is dropped regardless of anything else.
+	exclusions []*regexp.Regexp
+	// schemes is the set of schemes Capture is allowed to enqueue, e.g.
+	// "http", "https".
+	schemes map[string]bool
+	// excludeRelated, when set, keeps a page's embedded assets
+	// (analysis.LinkTypeAsset) only when their host matches the seed's.
+	excludeRelated bool
+}
+
+// NewScope compiles exclude into a Scope. excludeFromFile, if non-empty,
+// is read for one regexp per line; blank lines and lines starting with
+// "#" are ignored. schemes defaults to http/https when empty.
+func NewScope(exclude []string, excludeFromFile string, schemes []string, excludeRelated bool) (*Scope, error) {
+	scope := &Scope{
+		schemes:        make(map[string]bool),
+		excludeRelated: excludeRelated,
+	}
+
+	for _, pattern := range exclude {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		scope.exclusions = append(scope.exclusions, compiled)
+	}
+
+	if excludeFromFile != "" {
+		fromFile, err := parseExcludeFile(excludeFromFile)
+		if err != nil {
+			return nil, err
+		}
+
+		scope.exclusions = append(scope.exclusions, fromFile...)
+	}
+
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+
+	for _, scheme := range schemes {
+		scope.schemes[strings.ToLower(strings.TrimSpace(scheme))] = true
+	}
+
+	return scope, nil
+}
+
+func parseExcludeFile(path string) (exclusions []*regexp.Regexp, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		compiled, err := regexp.Compile(line)
+		if err != nil {
+			return nil, err
+		}
+
+		exclusions = append(exclusions, compiled)
+	}
+
+	return exclusions, scanner.Err()
+}
+
+// Allowed reports whether outlink, found on a page fetched from seed,
+// may be enqueued.
+func (s *Scope) Allowed(outlink analysis.Outlink, seed *url.URL) bool {
+	if !s.schemes[strings.ToLower(outlink.URL.Scheme)] {
+		return false
+	}
+
+	for _, exclusion := range s.exclusions {
+		if exclusion.MatchString(outlink.URL.String()) {
+			return false
+		}
+	}
+
+	if s.excludeRelated && outlink.Type == analysis.LinkTypeAsset && outlink.URL.Host != seed.Host {
+		return false
+	}
+
+	return true
+}
+
+// Filter returns the subset of outlinks that Allowed permits.
+func (s *Scope) Filter(outlinks []analysis.Outlink, seed *url.URL) (allowed []analysis.Outlink) {
+	for _, outlink := range outlinks {
+		if s.Allowed(outlink, seed) {
+			allowed = append(allowed, outlink)
+		}
+	}
+
+	return allowed
+}
@@ -0,0 +1,115 @@
+package crawl
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/CorentinB/Zeno/pkg/analysis"
+)
+
+func mustOutlink(t *testing.T, rawURL string, linkType analysis.LinkType) analysis.Outlink {
+	t.Helper()
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", rawURL, err)
+	}
+
+	return analysis.Outlink{URL: *parsed, Type: linkType}
+}
+
+func mustURL(t *testing.T, rawURL string) *url.URL {
+	t.Helper()
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", rawURL, err)
+	}
+
+	return parsed
+}
+
+func TestScopeAllowedSchemes(t *testing.T) {
+	scope, err := NewScope(nil, "", []string{"https"}, false)
+	if err != nil {
+		t.Fatalf("NewScope() error = %v", err)
+	}
+
+	seed := mustURL(t, "https://example.com/")
+
+	if !scope.Allowed(mustOutlink(t, "https://example.com/a", analysis.LinkTypeLink), seed) {
+		t.Error("Allowed() = false for https outlink, want true")
+	}
+	if scope.Allowed(mustOutlink(t, "ftp://example.com/a", analysis.LinkTypeLink), seed) {
+		t.Error("Allowed() = true for ftp outlink with schemes=[https], want false")
+	}
+}
+
+func TestScopeDefaultSchemes(t *testing.T) {
+	scope, err := NewScope(nil, "", nil, false)
+	if err != nil {
+		t.Fatalf("NewScope() error = %v", err)
+	}
+
+	seed := mustURL(t, "https://example.com/")
+
+	if !scope.Allowed(mustOutlink(t, "http://example.com/a", analysis.LinkTypeLink), seed) {
+		t.Error("Allowed() = false for http outlink with default schemes, want true")
+	}
+	if !scope.Allowed(mustOutlink(t, "https://example.com/a", analysis.LinkTypeLink), seed) {
+		t.Error("Allowed() = false for https outlink with default schemes, want true")
+	}
+}
+
+func TestScopeExclusions(t *testing.T) {
+	scope, err := NewScope([]string{`\.pdf$`}, "", nil, false)
+	if err != nil {
+		t.Fatalf("NewScope() error = %v", err)
+	}
+
+	seed := mustURL(t, "https://example.com/")
+
+	if scope.Allowed(mustOutlink(t, "https://example.com/doc.pdf", analysis.LinkTypeLink), seed) {
+		t.Error("Allowed() = true for an excluded .pdf outlink, want false")
+	}
+	if !scope.Allowed(mustOutlink(t, "https://example.com/page.html", analysis.LinkTypeLink), seed) {
+		t.Error("Allowed() = false for a non-excluded outlink, want true")
+	}
+}
+
+func TestScopeExcludeRelated(t *testing.T) {
+	scope, err := NewScope(nil, "", nil, true)
+	if err != nil {
+		t.Fatalf("NewScope() error = %v", err)
+	}
+
+	seed := mustURL(t, "https://example.com/")
+
+	if scope.Allowed(mustOutlink(t, "https://cdn.other.com/logo.png", analysis.LinkTypeAsset), seed) {
+		t.Error("Allowed() = true for a cross-host asset with --exclude-related, want false")
+	}
+	if !scope.Allowed(mustOutlink(t, "https://example.com/logo.png", analysis.LinkTypeAsset), seed) {
+		t.Error("Allowed() = false for a same-host asset with --exclude-related, want true")
+	}
+	if !scope.Allowed(mustOutlink(t, "https://cdn.other.com/page", analysis.LinkTypeLink), seed) {
+		t.Error("Allowed() = false for a cross-host navigational link with --exclude-related, want true")
+	}
+}
+
+func TestScopeFilter(t *testing.T) {
+	scope, err := NewScope([]string{`\.pdf$`}, "", nil, false)
+	if err != nil {
+		t.Fatalf("NewScope() error = %v", err)
+	}
+
+	seed := mustURL(t, "https://example.com/")
+	outlinks := []analysis.Outlink{
+		mustOutlink(t, "https://example.com/a.html", analysis.LinkTypeLink),
+		mustOutlink(t, "https://example.com/b.pdf", analysis.LinkTypeLink),
+	}
+
+	allowed := scope.Filter(outlinks, seed)
+	if len(allowed) != 1 || allowed[0].URL.String() != "https://example.com/a.html" {
+		t.Fatalf("Filter() = %v, want only https://example.com/a.html", allowed)
+	}
+}
@@ -2,6 +2,7 @@ package crawl
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httputil"
@@ -17,30 +18,39 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// dumpResponseToFile is like httputil.DumpResponse but dumps the response directly
-// to a file and return its path
-func (c *Crawl) dumpResponseToFile(resp *http.Response) (string, error) {
-	var err error
-
-	// Generate a file on disk with a unique name
+// dumpBodyToFile streams body to a uniquely-named file under
+// JobPath/temp via io.Copy, so that not a single response body - however
+// large or chunked - is ever held fully in RAM. It returns the open file
+// seeked back to the start, ready to be read by the link extractor and
+// then, after another Seek(0), by the WARC writer.
+func (c *Crawl) dumpBodyToFile(body io.Reader) (*os.File, error) {
 	UUID := uuid.NewV4()
 	filePath := filepath.Join(c.JobPath, "temp", UUID.String()+".temp")
-	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer file.Close()
 
-	// Write the response to the file directly
-	err = resp.Write(file)
-	if err != nil {
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
 		os.Remove(filePath)
-		return "", err
+		return nil, err
 	}
 
-	return filePath, nil
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		os.Remove(filePath)
+		return nil, err
+	}
+
+	return file, nil
 }
 
+// defaultWARCMaxSizeMB is the post-compression segment size used when
+// --output-max-size isn't given.
+const defaultWARCMaxSizeMB = 100
+
 func (c *Crawl) initWARCWriter() {
 	var rotatorSettings = warc.NewRotatorSettings()
 	var err error
@@ -48,9 +58,14 @@ func (c *Crawl) initWARCWriter() {
 	os.MkdirAll(path.Join(c.JobPath, "temp"), os.ModePerm)
 	go c.tempFilesCleaner()
 
+	if c.WARCMaxSizeMB == 0 {
+		c.WARCMaxSizeMB = defaultWARCMaxSizeMB
+	}
+
 	rotatorSettings.OutputDirectory = path.Join(c.JobPath, "warcs")
 	rotatorSettings.Compression = "GZIP"
 	rotatorSettings.Prefix = c.WARCPrefix
+	rotatorSettings.WarcSize = float64(c.WARCMaxSizeMB)
 	rotatorSettings.WarcinfoContent.Set("software", "Zeno")
 	if len(c.WARCOperator) > 0 {
 		rotatorSettings.WarcinfoContent.Set("operator", c.WARCOperator)
@@ -128,43 +143,55 @@ func (c *Crawl) writeWARCFromConnection(req, resp *io.PipeReader, URL *url.URL)
 	return nil
 }
 
-func (c *Crawl) writeWARC(resp *http.Response) (string, error) {
+// dumpResponseStatusAndHeader renders the status line and headers of
+// resp the same way http.Response.Write would, without touching
+// resp.Body - so it can be prepended to a body already streamed to disk
+// separately, instead of buffering the whole response to get this.
+func dumpResponseStatusAndHeader(resp *http.Response) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "HTTP/%d.%d %s\r\n", resp.ProtoMajor, resp.ProtoMinor, resp.Status)
+
+	if err := resp.Header.Write(&buf); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString("\r\n")
+
+	return buf.Bytes(), nil
+}
+
+// writeWARC archives resp as a WARC request/response record pair.
+// bodyFile is the response body already streamed to disk by
+// dumpBodyToFile and shared with the link extractor - writeWARC seeks it
+// back to the start and prepends the status line and headers ahead of
+// it, so the response record holds a full HTTP message (status code,
+// Content-Type, Set-Cookie, etc.) and not just the bare payload. The
+// size heuristic that used to gate disk vs in-memory handling is gone:
+// every response, regardless of size, goes through bodyFile.
+func (c *Crawl) writeWARC(resp *http.Response, bodyFile *os.File) (err error) {
 	var batch = warc.NewRecordBatch()
-	var requestDump []byte
-	var responseDump []byte
-	var responsePath string
-	var err error
+
+	if _, err = bodyFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	headerBytes, err := dumpResponseStatusAndHeader(resp)
+	if err != nil {
+		return err
+	}
 
 	// Initialize the response record
 	var responseRecord = warc.NewRecord()
 	responseRecord.Header.Set("WARC-Type", "response")
 	responseRecord.Header.Set("WARC-Target-URI", url.QueryEscape(resp.Request.URL.String()))
 	responseRecord.Header.Set("Content-Type", "application/http; msgtype=response")
-
-	// If the Content-Length is unknown or if it is higher than 2MB, then
-	// we process the response directly on disk to not risk maxing-out the RAM.
-	// Else, we use the httputil.DumpResponse function to dump the response.
-	if resp.ContentLength == -1 || resp.ContentLength > 2097152 {
-		responsePath, err = c.dumpResponseToFile(resp)
-		if err != nil {
-			return responsePath, err
-		}
-
-		responseRecord.PayloadPath = responsePath
-	} else {
-		responseDump, err = httputil.DumpResponse(resp, true)
-		if err != nil {
-			return responsePath, err
-		}
-
-		responseRecord.Content = strings.NewReader(string(responseDump))
-	}
+	responseRecord.Content = io.MultiReader(bytes.NewReader(headerBytes), bodyFile)
 
 	// Dump request
-	requestDump, err = httputil.DumpRequestOut(resp.Request, true)
+	requestDump, err := httputil.DumpRequestOut(resp.Request, true)
 	if err != nil {
-		os.Remove(responsePath)
-		return responsePath, err
+		return err
 	}
 
 	// Initialize the request record
@@ -179,16 +206,13 @@ func (c *Crawl) writeWARC(resp *http.Response) (string, error) {
 	// Append records to the record batch
 	batch.Records = append(batch.Records, responseRecord, requestRecord)
 
-	// If we used a temporary file on disk, we create a "response channel"
-	// that we fit in the batch, so the WARC writer is able to tell us when
-	// the writing is done, so we can delete the temporary file safely
-	if responsePath != "" {
-		batch.Done = make(chan bool)
-		c.WARCWriter <- batch
-		<-batch.Done
-	} else {
-		c.WARCWriter <- batch
-	}
+	// We always go through a temporary file on disk now, so we always
+	// create a "response channel" that we fit in the batch, so the WARC
+	// writer is able to tell us when the writing is done and it's safe
+	// for the caller to delete bodyFile.
+	batch.Done = make(chan bool)
+	c.WARCWriter <- batch
+	<-batch.Done
 
-	return responsePath, nil
+	return nil
 }
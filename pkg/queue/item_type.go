@@ -0,0 +1,16 @@
+package queue
+
+// ItemType records whether an Item was discovered as a plain
+// navigational link or as a page's embedded asset (image, stylesheet,
+// script...), so scoping rules like --exclude-related can tell the two
+// apart.
+type ItemType string
+
+const (
+	// ItemTypeSeed is a crawl's starting point.
+	ItemTypeSeed ItemType = "seed"
+	// ItemTypeLink is a URL discovered via a navigational link.
+	ItemTypeLink ItemType = "link"
+	// ItemTypeAsset is a URL discovered as a page's embedded asset.
+	ItemTypeAsset ItemType = "asset"
+)
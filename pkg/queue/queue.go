@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"net/url"
+	"sync"
+)
+
+// Item represents a single URL in the crawl frontier, along with the
+// context of how it was discovered.
+type Item struct {
+	URL        url.URL
+	Hash       string
+	Hop        uint8
+	Type       ItemType
+	ParentItem *Item
+}
+
+// Queue is the in-memory frontier that crawl workers pull Items from.
+type Queue struct {
+	mu    sync.Mutex
+	items []*Item
+}
+
+// NewQueue returns an empty, ready to use Queue.
+func NewQueue() *Queue {
+	return &Queue{}
+}
+
+// Enqueue appends item to the back of the frontier.
+func (q *Queue) Enqueue(item *Item) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.items = append(q.items, item)
+}
+
+// Dequeue pops the item at the front of the frontier. The second return
+// value is false if the frontier is empty.
+func (q *Queue) Dequeue() (*Item, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.items) == 0 {
+		return nil, false
+	}
+
+	item := q.items[0]
+	q.items = q.items[1:]
+
+	return item, true
+}
+
+// Len reports how many items are currently waiting in the frontier.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.items)
+}
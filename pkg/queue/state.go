@@ -0,0 +1,192 @@
+package queue
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"go.etcd.io/bbolt"
+)
+
+// ItemStatus represents where an Item is in its lifecycle inside the
+// on-disk frontier database.
+type ItemStatus string
+
+const (
+	// StatusPending means the item has been recorded but not fetched yet.
+	StatusPending ItemStatus = "pending"
+	// StatusInProgress means a worker has picked up the item but hasn't
+	// finished capturing it.
+	StatusInProgress ItemStatus = "in-progress"
+	// StatusDone means the item was fetched and its WARC record(s) were
+	// flushed.
+	StatusDone ItemStatus = "done"
+)
+
+var frontierBucket = []byte("frontier")
+
+// StateRecord is the persisted representation of an Item, keyed by its
+// hash in the state database.
+type StateRecord struct {
+	URL    string     `json:"url"`
+	Hash   string     `json:"hash"`
+	Hop    uint8      `json:"hop"`
+	Parent string     `json:"parent"`
+	Status ItemStatus `json:"status"`
+}
+
+// StateDB persists the crawl frontier to disk so that an interrupted
+// crawl can be resumed with --resume without losing progress or
+// re-fetching URLs it already completed.
+type StateDB struct {
+	db *bbolt.DB
+}
+
+// OpenStateDB opens (and creates if necessary) the frontier database
+// under jobPath/state.
+func OpenStateDB(jobPath string) (*StateDB, error) {
+	statePath := filepath.Join(jobPath, "state")
+	if err := os.MkdirAll(statePath, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	db, err := bbolt.Open(filepath.Join(statePath, "frontier.db"), 0644, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(frontierBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &StateDB{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *StateDB) Close() error {
+	return s.db.Close()
+}
+
+// Known reports whether hash has already been recorded in the frontier
+// database, regardless of its current status. Callers use this to avoid
+// re-queueing a URL they've already discovered once.
+func (s *StateDB) Known(hash string) (bool, error) {
+	var known bool
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		known = tx.Bucket(frontierBucket).Get([]byte(hash)) != nil
+		return nil
+	})
+
+	return known, err
+}
+
+// Put records an item as pending. It is called as soon as an item is
+// enqueued, before it is ever dequeued for capture. Put only inserts: if
+// the hash is already recorded - because the same URL was discovered
+// twice in one run, or because it was persisted by a previous run -
+// writing over it here would reset an in-progress or already-done item
+// back to pending, causing it to be re-fetched and re-archived.
+func (s *StateDB) Put(item *Item) error {
+	record := &StateRecord{
+		URL:    item.URL.String(),
+		Hash:   item.Hash,
+		Hop:    item.Hop,
+		Status: StatusPending,
+	}
+
+	if item.ParentItem != nil {
+		record.Parent = item.ParentItem.Hash
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(frontierBucket)
+
+		if bucket.Get([]byte(record.Hash)) != nil {
+			return nil
+		}
+
+		return bucket.Put([]byte(record.Hash), data)
+	})
+}
+
+// Transition atomically moves an already-recorded item to a new status.
+// It is a no-op if the hash has never been seen.
+func (s *StateDB) Transition(hash string, status ItemStatus) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(frontierBucket)
+
+		data := bucket.Get([]byte(hash))
+		if data == nil {
+			return nil
+		}
+
+		var record StateRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+		record.Status = status
+
+		data, err := json.Marshal(&record)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(hash), data)
+	})
+}
+
+// IsDone reports whether a hash has already been fetched and flushed to
+// WARC, so the crawler can skip it on --resume.
+func (s *StateDB) IsDone(hash string) (bool, error) {
+	var done bool
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(frontierBucket).Get([]byte(hash))
+		if data == nil {
+			return nil
+		}
+
+		var record StateRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+
+		done = record.Status == StatusDone
+		return nil
+	})
+
+	return done, err
+}
+
+// Outstanding returns every record that isn't marked done, in the order
+// BoltDB stores them, so --resume can replay them back into the
+// in-memory queue.
+func (s *StateDB) Outstanding() (records []*StateRecord, err error) {
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(frontierBucket).ForEach(func(_, v []byte) error {
+			var record StateRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+
+			if record.Status != StatusDone {
+				records = append(records, &record)
+			}
+
+			return nil
+		})
+	})
+
+	return records, err
+}
@@ -0,0 +1,113 @@
+package queue
+
+import (
+	"net/url"
+	"testing"
+)
+
+func newTestStateDB(t *testing.T) *StateDB {
+	t.Helper()
+
+	db, err := OpenStateDB(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenStateDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func newTestItem(t *testing.T, rawURL, hash string) *Item {
+	t.Helper()
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", rawURL, err)
+	}
+
+	return &Item{URL: *parsed, Hash: hash}
+}
+
+func TestStateDBPutIsInsertOnly(t *testing.T) {
+	db := newTestStateDB(t)
+	item := newTestItem(t, "https://example.com/", "hash-1")
+
+	if err := db.Put(item); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := db.Transition(item.Hash, StatusDone); err != nil {
+		t.Fatalf("Transition() error = %v", err)
+	}
+
+	// Re-discovering the same URL (a repeated nav link, say) must not
+	// reset its status back to pending.
+	if err := db.Put(item); err != nil {
+		t.Fatalf("second Put() error = %v", err)
+	}
+
+	done, err := db.IsDone(item.Hash)
+	if err != nil {
+		t.Fatalf("IsDone() error = %v", err)
+	}
+	if !done {
+		t.Fatalf("IsDone() = false, want true after Put() re-inserted an already-done hash")
+	}
+}
+
+func TestStateDBKnown(t *testing.T) {
+	db := newTestStateDB(t)
+	item := newTestItem(t, "https://example.com/", "hash-1")
+
+	known, err := db.Known(item.Hash)
+	if err != nil {
+		t.Fatalf("Known() error = %v", err)
+	}
+	if known {
+		t.Fatalf("Known() = true before Put(), want false")
+	}
+
+	if err := db.Put(item); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	known, err = db.Known(item.Hash)
+	if err != nil {
+		t.Fatalf("Known() error = %v", err)
+	}
+	if !known {
+		t.Fatalf("Known() = false after Put(), want true")
+	}
+}
+
+func TestStateDBTransitionUnknownHashIsNoop(t *testing.T) {
+	db := newTestStateDB(t)
+
+	if err := db.Transition("does-not-exist", StatusDone); err != nil {
+		t.Fatalf("Transition() on unknown hash error = %v, want nil", err)
+	}
+}
+
+func TestStateDBOutstandingExcludesDone(t *testing.T) {
+	db := newTestStateDB(t)
+
+	pending := newTestItem(t, "https://example.com/pending", "pending-hash")
+	done := newTestItem(t, "https://example.com/done", "done-hash")
+
+	if err := db.Put(pending); err != nil {
+		t.Fatalf("Put(pending) error = %v", err)
+	}
+	if err := db.Put(done); err != nil {
+		t.Fatalf("Put(done) error = %v", err)
+	}
+	if err := db.Transition(done.Hash, StatusDone); err != nil {
+		t.Fatalf("Transition(done) error = %v", err)
+	}
+
+	records, err := db.Outstanding()
+	if err != nil {
+		t.Fatalf("Outstanding() error = %v", err)
+	}
+	if len(records) != 1 || records[0].Hash != pending.Hash {
+		t.Fatalf("Outstanding() = %+v, want only %q", records, pending.Hash)
+	}
+}